@@ -0,0 +1,71 @@
+// Package gokit implements a metrics.Recorder on top of the go-kit metrics
+// facade (https://pkg.go.dev/github.com/go-kit/kit/metrics), so the
+// middleware can push metrics to any backend go-kit supports (statsd,
+// dogstatsd, InfluxDB, CloudWatch...) without this repository having to
+// maintain a dedicated implementation for each one of them.
+package gokit
+
+import (
+	"context"
+	"time"
+
+	kitmetrics "github.com/go-kit/kit/metrics"
+
+	"github.com/slok/go-http-metrics/metrics"
+)
+
+// Config has the go-kit metric instances the Recorder will use to record the
+// HTTP measurements, one per RED signal. All the fields are required.
+type Config struct {
+	// DurationHistogram is used to observe the HTTP request duration in seconds.
+	DurationHistogram kitmetrics.Histogram
+	// ResponseSizeHistogram is used to observe the HTTP response size in bytes.
+	ResponseSizeHistogram kitmetrics.Histogram
+	// InflightRequestsGauge is used to track the number of inflight requests.
+	InflightRequestsGauge kitmetrics.Gauge
+	// ErrorsCounter is used to count the requests classified as an error.
+	ErrorsCounter kitmetrics.Counter
+	// SaturationGauge is used to track how close to its capacity a service is.
+	SaturationGauge kitmetrics.Gauge
+}
+
+type recorder struct {
+	cfg Config
+}
+
+// New returns a metrics.Recorder that records the measurements using the
+// go-kit metric instances set on the Config, letting the user bucket the
+// histograms and back them with whatever go-kit backend they want (statsd,
+// dogstatsd, InfluxDB, CloudWatch...).
+func New(cfg Config) metrics.Recorder {
+	return recorder{cfg: cfg}
+}
+
+// ObserveHTTPRequestDuration satisfies metrics.Recorder interface.
+func (r recorder) ObserveHTTPRequestDuration(_ context.Context, props metrics.HTTPReqProperties, duration time.Duration) {
+	r.cfg.DurationHistogram.With(reqLabelValues(props)...).Observe(duration.Seconds())
+}
+
+// ObserveHTTPResponseSize satisfies metrics.Recorder interface.
+func (r recorder) ObserveHTTPResponseSize(_ context.Context, props metrics.HTTPReqProperties, sizeBytes int64) {
+	r.cfg.ResponseSizeHistogram.With(reqLabelValues(props)...).Observe(float64(sizeBytes))
+}
+
+// AddInflightRequests satisfies metrics.Recorder interface.
+func (r recorder) AddInflightRequests(_ context.Context, props metrics.HTTPProperties, quantity int) {
+	r.cfg.InflightRequestsGauge.With("service", props.Service, "id", props.ID).Add(float64(quantity))
+}
+
+// AddHTTPRequestErrors satisfies metrics.Recorder interface.
+func (r recorder) AddHTTPRequestErrors(_ context.Context, props metrics.HTTPReqProperties, quantity int) {
+	r.cfg.ErrorsCounter.With(reqLabelValues(props)...).Add(float64(quantity))
+}
+
+// ObserveSaturation satisfies metrics.Recorder interface.
+func (r recorder) ObserveSaturation(_ context.Context, props metrics.HTTPProperties, saturation float64) {
+	r.cfg.SaturationGauge.With("service", props.Service, "id", props.ID).Set(saturation)
+}
+
+func reqLabelValues(props metrics.HTTPReqProperties) []string {
+	return []string{"service", props.Service, "id", props.ID, "method", props.Method, "code", props.Code}
+}
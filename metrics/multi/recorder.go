@@ -0,0 +1,58 @@
+// Package multi provides a metrics.Recorder that fans out every measurement
+// to multiple child recorders, so a single middleware can push metrics to
+// more than one backend at the same time (e.g. Prometheus locally and statsd
+// in production).
+package multi
+
+import (
+	"context"
+	"time"
+
+	"github.com/slok/go-http-metrics/metrics"
+)
+
+// Recorder is a metrics.Recorder that measures using N child recorders.
+type Recorder struct {
+	recorders []metrics.Recorder
+}
+
+// New returns a new Recorder that fans out every call to all the given
+// recorders, in the order they are passed.
+func New(recorders ...metrics.Recorder) Recorder {
+	return Recorder{recorders: recorders}
+}
+
+// ObserveHTTPRequestDuration satisfies metrics.Recorder interface.
+func (r Recorder) ObserveHTTPRequestDuration(ctx context.Context, props metrics.HTTPReqProperties, duration time.Duration) {
+	for _, rec := range r.recorders {
+		rec.ObserveHTTPRequestDuration(ctx, props, duration)
+	}
+}
+
+// ObserveHTTPResponseSize satisfies metrics.Recorder interface.
+func (r Recorder) ObserveHTTPResponseSize(ctx context.Context, props metrics.HTTPReqProperties, sizeBytes int64) {
+	for _, rec := range r.recorders {
+		rec.ObserveHTTPResponseSize(ctx, props, sizeBytes)
+	}
+}
+
+// AddInflightRequests satisfies metrics.Recorder interface.
+func (r Recorder) AddInflightRequests(ctx context.Context, props metrics.HTTPProperties, quantity int) {
+	for _, rec := range r.recorders {
+		rec.AddInflightRequests(ctx, props, quantity)
+	}
+}
+
+// AddHTTPRequestErrors satisfies metrics.Recorder interface.
+func (r Recorder) AddHTTPRequestErrors(ctx context.Context, props metrics.HTTPReqProperties, quantity int) {
+	for _, rec := range r.recorders {
+		rec.AddHTTPRequestErrors(ctx, props, quantity)
+	}
+}
+
+// ObserveSaturation satisfies metrics.Recorder interface.
+func (r Recorder) ObserveSaturation(ctx context.Context, props metrics.HTTPProperties, saturation float64) {
+	for _, rec := range r.recorders {
+		rec.ObserveSaturation(ctx, props, saturation)
+	}
+}
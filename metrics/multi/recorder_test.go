@@ -0,0 +1,61 @@
+package multi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/go-http-metrics/metrics"
+	"github.com/slok/go-http-metrics/metrics/multi"
+)
+
+type fakeRecorder struct {
+	durationCalls   int
+	sizeCalls       int
+	inflightCalls   int
+	errorCalls      int
+	saturationCalls int
+}
+
+func (f *fakeRecorder) ObserveHTTPRequestDuration(_ context.Context, _ metrics.HTTPReqProperties, _ time.Duration) {
+	f.durationCalls++
+}
+
+func (f *fakeRecorder) ObserveHTTPResponseSize(_ context.Context, _ metrics.HTTPReqProperties, _ int64) {
+	f.sizeCalls++
+}
+
+func (f *fakeRecorder) AddInflightRequests(_ context.Context, _ metrics.HTTPProperties, _ int) {
+	f.inflightCalls++
+}
+
+func (f *fakeRecorder) AddHTTPRequestErrors(_ context.Context, _ metrics.HTTPReqProperties, _ int) {
+	f.errorCalls++
+}
+
+func (f *fakeRecorder) ObserveSaturation(_ context.Context, _ metrics.HTTPProperties, _ float64) {
+	f.saturationCalls++
+}
+
+func TestRecorder(t *testing.T) {
+	r1 := &fakeRecorder{}
+	r2 := &fakeRecorder{}
+	rec := multi.New(r1, r2)
+
+	ctx := context.TODO()
+	rec.ObserveHTTPRequestDuration(ctx, metrics.HTTPReqProperties{}, time.Second)
+	rec.ObserveHTTPResponseSize(ctx, metrics.HTTPReqProperties{}, 42)
+	rec.AddInflightRequests(ctx, metrics.HTTPProperties{}, 1)
+	rec.AddHTTPRequestErrors(ctx, metrics.HTTPReqProperties{}, 1)
+	rec.ObserveSaturation(ctx, metrics.HTTPProperties{}, 0.5)
+
+	for _, r := range []*fakeRecorder{r1, r2} {
+		assert.Equal(t, 1, r.durationCalls)
+		assert.Equal(t, 1, r.sizeCalls)
+		assert.Equal(t, 1, r.inflightCalls)
+		assert.Equal(t, 1, r.errorCalls)
+		assert.Equal(t, 1, r.saturationCalls)
+	}
+}
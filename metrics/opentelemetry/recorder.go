@@ -0,0 +1,171 @@
+// Package opentelemetry implements a metrics.Recorder on top of the
+// OpenTelemetry Metrics SDK (go.opentelemetry.io/otel/metric), so the
+// middleware can export HTTP metrics to any OTel-compatible backend.
+//
+// When the request's context.Context carries an active span, the
+// OpenTelemetry SDK attaches it as an exemplar on the histogram
+// observations, letting tools like Grafana/Tempo jump from a latency bucket
+// straight to the trace that produced it.
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/slok/go-http-metrics/metrics"
+)
+
+const meterName = "github.com/slok/go-http-metrics/metrics/opentelemetry"
+
+// Config is the configuration for creating a new Recorder.
+type Config struct {
+	// Meter is the OpenTelemetry meter used to create the instruments. By
+	// default the global meter provider is used.
+	Meter metric.Meter
+	// EmitSemanticConventions, when true, additionally records every metric
+	// with the attribute names defined by the OTel HTTP semantic conventions
+	// (http.request.method, http.response.status_code, http.route), alongside
+	// this package's own attribute names, so dashboards built against either
+	// convention keep working. Inflight requests and saturation only get
+	// http.route, and http.response.status_code is only added when the code
+	// label is a plain number (not when it's grouped, e.g. `2xx`).
+	EmitSemanticConventions bool
+}
+
+func (c *Config) defaults() {
+	if c.Meter == nil {
+		c.Meter = otel.GetMeterProvider().Meter(meterName)
+	}
+}
+
+type recorder struct {
+	cfg Config
+
+	requestDuration  metric.Float64Histogram
+	responseSize     metric.Int64Histogram
+	inflightRequests metric.Int64UpDownCounter
+	requestErrors    metric.Int64Counter
+	saturation       metric.Float64Gauge
+}
+
+// New returns a metrics.Recorder that records the measurements using the
+// OpenTelemetry Metrics SDK instruments created from Config.Meter.
+func New(cfg Config) (metrics.Recorder, error) {
+	cfg.defaults()
+
+	requestDuration, err := cfg.Meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Measures the duration of inbound HTTP requests."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request duration histogram: %w", err)
+	}
+
+	responseSize, err := cfg.Meter.Int64Histogram(
+		"http.server.response.size",
+		metric.WithDescription("Measures the size of HTTP response bodies."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create response size histogram: %w", err)
+	}
+
+	inflightRequests, err := cfg.Meter.Int64UpDownCounter(
+		"http.server.active_requests",
+		metric.WithDescription("Measures the number of concurrent HTTP requests that are currently in-flight."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create inflight requests counter: %w", err)
+	}
+
+	requestErrors, err := cfg.Meter.Int64Counter(
+		"http.server.request.errors",
+		metric.WithDescription("Counts the number of HTTP requests classified as an error."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request errors counter: %w", err)
+	}
+
+	saturation, err := cfg.Meter.Float64Gauge(
+		"http.server.saturation",
+		metric.WithDescription("Measures how close to its capacity a service is, normally between 0 and 1."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create saturation gauge: %w", err)
+	}
+
+	return recorder{
+		cfg:              cfg,
+		requestDuration:  requestDuration,
+		responseSize:     responseSize,
+		inflightRequests: inflightRequests,
+		requestErrors:    requestErrors,
+		saturation:       saturation,
+	}, nil
+}
+
+// ObserveHTTPRequestDuration satisfies metrics.Recorder interface.
+func (r recorder) ObserveHTTPRequestDuration(ctx context.Context, props metrics.HTTPReqProperties, duration time.Duration) {
+	r.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(r.reqAttributes(props)...))
+}
+
+// ObserveHTTPResponseSize satisfies metrics.Recorder interface.
+func (r recorder) ObserveHTTPResponseSize(ctx context.Context, props metrics.HTTPReqProperties, sizeBytes int64) {
+	r.responseSize.Record(ctx, sizeBytes, metric.WithAttributes(r.reqAttributes(props)...))
+}
+
+// AddInflightRequests satisfies metrics.Recorder interface.
+func (r recorder) AddInflightRequests(ctx context.Context, props metrics.HTTPProperties, quantity int) {
+	r.inflightRequests.Add(ctx, int64(quantity), metric.WithAttributes(r.attributes(props)...))
+}
+
+// AddHTTPRequestErrors satisfies metrics.Recorder interface.
+func (r recorder) AddHTTPRequestErrors(ctx context.Context, props metrics.HTTPReqProperties, quantity int) {
+	r.requestErrors.Add(ctx, int64(quantity), metric.WithAttributes(r.reqAttributes(props)...))
+}
+
+// ObserveSaturation satisfies metrics.Recorder interface.
+func (r recorder) ObserveSaturation(ctx context.Context, props metrics.HTTPProperties, saturation float64) {
+	r.saturation.Record(ctx, saturation, metric.WithAttributes(r.attributes(props)...))
+}
+
+func (r recorder) attributes(props metrics.HTTPProperties) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("service", props.Service),
+		attribute.String("id", props.ID),
+	}
+
+	if r.cfg.EmitSemanticConventions {
+		attrs = append(attrs, attribute.String("http.route", props.ID))
+	}
+
+	return attrs
+}
+
+func (r recorder) reqAttributes(props metrics.HTTPReqProperties) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("service", props.Service),
+		attribute.String("id", props.ID),
+		attribute.String("method", props.Method),
+		attribute.String("code", props.Code),
+	}
+
+	if r.cfg.EmitSemanticConventions {
+		attrs = append(attrs,
+			attribute.String("http.request.method", props.Method),
+			attribute.String("http.route", props.ID),
+		)
+		if code, err := strconv.Atoi(props.Code); err == nil {
+			attrs = append(attrs, attribute.Int("http.response.status_code", code))
+		}
+	}
+
+	return attrs
+}
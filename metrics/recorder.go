@@ -0,0 +1,70 @@
+// Package metrics defines the recorder abstraction the middleware uses to
+// measure HTTP requests, independently of the metrics backend (Prometheus,
+// statsd, OpenTelemetry...) the user wants to send the measurements to.
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// Recorder knows how to record and measure the metrics. This is an interface
+// abstraction so the user can use the metrics backend it wants (Prometheus,
+// statsd, OpenTelemetry...) by implementing this interface.
+type Recorder interface {
+	// ObserveHTTPRequestDuration measures the duration of an HTTP request.
+	ObserveHTTPRequestDuration(ctx context.Context, props HTTPReqProperties, duration time.Duration)
+	// ObserveHTTPResponseSize measures the size of an HTTP response in bytes.
+	ObserveHTTPResponseSize(ctx context.Context, props HTTPReqProperties, sizeBytes int64)
+	// AddInflightRequests increments and decrements the number of inflight requests.
+	AddInflightRequests(ctx context.Context, props HTTPProperties, quantity int)
+	// AddHTTPRequestErrors increments the number of requests classified as an
+	// error (see the errors golden signal).
+	AddHTTPRequestErrors(ctx context.Context, props HTTPReqProperties, quantity int)
+	// ObserveSaturation measures how close to its capacity a service is (see
+	// the saturation golden signal), normally a value between 0 and 1.
+	ObserveSaturation(ctx context.Context, props HTTPProperties, saturation float64)
+}
+
+// HTTPReqProperties are the properties of an HTTP request/response cycle that
+// are used as the labels/tags of the different metrics.
+type HTTPReqProperties struct {
+	// Service is an optional identifier for the metrics, this can be useful if
+	// a same service has multiple servers (e.g API, metrics and healthchecks).
+	Service string
+	// ID is the identifier of the handler, normally the URL path or the handler
+	// ID explicitly set by the user.
+	ID string
+	// Method is the HTTP method of the request.
+	Method string
+	// Code is the HTTP status code the response finished with.
+	Code string
+}
+
+// HTTPProperties are the properties of an HTTP measurement that isn't tied to
+// a finished request/response cycle (e.g. inflight requests).
+type HTTPProperties struct {
+	// Service is an optional identifier for the metrics, this can be useful if
+	// a same service has multiple servers (e.g API, metrics and healthchecks).
+	Service string
+	// ID is the identifier of the handler, normally the URL path or the handler
+	// ID explicitly set by the user.
+	ID string
+}
+
+type dummyRecorder int
+
+// Dummy is a Recorder that doesn't record anything, it's used as the default
+// Recorder so the middleware never needs to nil check it.
+const Dummy = dummyRecorder(0)
+
+func (dummyRecorder) ObserveHTTPRequestDuration(_ context.Context, _ HTTPReqProperties, _ time.Duration) {
+}
+
+func (dummyRecorder) ObserveHTTPResponseSize(_ context.Context, _ HTTPReqProperties, _ int64) {}
+
+func (dummyRecorder) AddInflightRequests(_ context.Context, _ HTTPProperties, _ int) {}
+
+func (dummyRecorder) AddHTTPRequestErrors(_ context.Context, _ HTTPReqProperties, _ int) {}
+
+func (dummyRecorder) ObserveSaturation(_ context.Context, _ HTTPProperties, _ float64) {}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"path"
+	"regexp"
+)
+
+// NewGlobPathFilter returns a Config.Filter that skips measuring requests
+// whose URL path matches any of the given glob patterns (as implemented by
+// path.Match). This is useful to exclude endpoints like `/healthz`,
+// `/metrics`, or static assets (`/assets/*.js`) from being measured.
+//
+// A malformed pattern (path.ErrBadPattern) is treated as "never matches"
+// rather than failing at request time, so validate patterns (e.g. with
+// path.Match against a sample path) when they come from user input.
+func NewGlobPathFilter(patterns ...string) func(r Reporter) bool {
+	return func(r Reporter) bool {
+		urlPath := r.URLPath()
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, urlPath); ok {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// NewRegexPathFilter returns a Config.Filter that skips measuring requests
+// whose URL path matches any of the given regexes.
+func NewRegexPathFilter(patterns ...*regexp.Regexp) func(r Reporter) bool {
+	return func(r Reporter) bool {
+		urlPath := r.URLPath()
+		for _, pattern := range patterns {
+			if pattern.MatchString(urlPath) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
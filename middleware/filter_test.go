@@ -0,0 +1,26 @@
+package middleware_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/go-http-metrics/middleware"
+)
+
+func TestNewGlobPathFilter(t *testing.T) {
+	filter := middleware.NewGlobPathFilter("/healthz", "/assets/*.js")
+
+	assert.False(t, filter(fakeReporter{urlPath: "/healthz"}))
+	assert.False(t, filter(fakeReporter{urlPath: "/assets/app.js"}))
+	assert.True(t, filter(fakeReporter{urlPath: "/api/v1/users"}))
+}
+
+func TestNewRegexPathFilter(t *testing.T) {
+	filter := middleware.NewRegexPathFilter(regexp.MustCompile(`^/(healthz|metrics)$`))
+
+	assert.False(t, filter(fakeReporter{urlPath: "/healthz"}))
+	assert.False(t, filter(fakeReporter{urlPath: "/metrics"}))
+	assert.True(t, filter(fakeReporter{urlPath: "/api/v1/users"}))
+}
@@ -7,9 +7,10 @@ package middleware
 import (
 	"context"
 	"fmt"
-	"regexp"
+	"math/rand"
 	"strconv"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/slok/go-http-metrics/metrics"
@@ -34,12 +35,72 @@ type Config struct {
 	// DisableMeasureInflight will disable the recording metrics about the inflight requests number,
 	// by default measuring inflights is enabled (`DisableMeasureInflight` is false).
 	DisableMeasureInflight bool
+	// PathNormalizer is used to obtain the `ID` label when no handler ID has been
+	// set explicitly, it receives the Reporter of the request so it can use the
+	// URL path, the router pattern, or anything else exposed by the Reporter to
+	// produce a bounded-cardinality value.
+	// By default a NumericSegmentNormalizer is used.
+	PathNormalizer PathNormalizer
+	// ErrorClassifier decides whether a request should be counted on the
+	// errors golden signal. By default a request is classified as an error
+	// when its status code is >= 500 or when the Reporter exposes a non-nil
+	// error (see ErrReporter).
+	ErrorClassifier func(statusCode int, err error) bool
+	// MaxInflight is the maximum number of inflight requests this handler is
+	// expected to sustain, it's used to compute the saturation golden signal
+	// as `inflight requests / MaxInflight`. Ignored if SaturationFunc is set,
+	// and has no effect if DisableMeasureInflight is true.
+	MaxInflight int64
+	// SaturationFunc overrides the default MaxInflight-based saturation
+	// calculation, it receives the current number of inflight requests and
+	// returns the saturation, normally a value between 0 and 1. Has no effect
+	// if DisableMeasureInflight is true.
+	SaturationFunc func(inflightRequests int64) float64
+	// Filter, if set, is called before any measurement is taken and skips the
+	// whole request (inflight, duration, size, errors, saturation) when it
+	// returns false, e.g. to exclude `/healthz`, `/metrics` or static assets
+	// (see NewGlobPathFilter/NewRegexPathFilter).
+	Filter func(r Reporter) bool
+	// Sampler, if set, is called (after Filter) to decide whether a request
+	// that passed the filter is actually measured, it returns the probability,
+	// between 0 and 1, that the request will be measured. Useful to reduce the
+	// cardinality/bandwidth of very high QPS endpoints.
+	Sampler func(r Reporter) float64
 }
 
 func (c *Config) defaults() {
 	if c.Recorder == nil {
 		c.Recorder = metrics.Dummy
 	}
+	if c.PathNormalizer == nil {
+		c.PathNormalizer = NumericSegmentNormalizer{}
+	}
+	if c.ErrorClassifier == nil {
+		c.ErrorClassifier = defaultErrorClassifier
+	}
+	if c.SaturationFunc == nil && c.MaxInflight > 0 {
+		maxInflight := c.MaxInflight
+		c.SaturationFunc = func(inflightRequests int64) float64 {
+			return float64(inflightRequests) / float64(maxInflight)
+		}
+	}
+}
+
+func defaultErrorClassifier(statusCode int, err error) bool {
+	return err != nil || statusCode >= 500
+}
+
+// sampled decides, given a sampling rate between 0 and 1, whether the current
+// request should be measured.
+func sampled(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	return rand.Float64() < rate
 }
 
 // Middleware is a service that knows how to measure an HTTP handler by wrapping
@@ -50,55 +111,52 @@ func (c *Config) defaults() {
 // recieve a `Reporter` that knows how to get the data the Middleware service needs
 // to measure.
 type Middleware struct {
-	cfg Config
+	cfg      Config
+	inflight *inflightTracker
 }
 
 // New returns the a Middleware service.
 func New(cfg Config) Middleware {
 	cfg.defaults()
 
-	m := Middleware{cfg: cfg}
+	m := Middleware{cfg: cfg, inflight: newInflightTracker()}
 
 	return m
 }
 
-func FixPath(urlPath string) string {
-	if strings.Contains("urlPath", ".js") {
-		return ""
-	}
-	if strings.Contains("urlPath", ".css") {
-		return ""
-	}
-	if strings.Contains("urlPath", ".png") {
-		return ""
-	}
-	if strings.Contains("urlPath", ".jpg") {
-		return ""
-	}
-	if strings.Contains("urlPath", ".html") {
-		return ""
-	}
-	if strings.Contains("urlPath", ".json") {
-		return ""
-	}
-	tmpPaths := strings.Split(urlPath, "/")
-	n := len(tmpPaths)
-	if n <= 4 {
-		return urlPath
-	}
-	pathResult := ""
-	for i := 0; i < n; i++ {
-		if regexp.MustCompile(`\d`).MatchString(tmpPaths[i]) && !strings.HasPrefix(tmpPaths[i], "v") {
-			if i == n-1 {
-				pathResult += "detail"
-			}
-		} else {
-			pathResult += tmpPaths[i] + "/"
-		}
+// inflightTracker keeps one inflight-requests counter per handler ID, so the
+// saturation golden signal (Config.MaxInflight/SaturationFunc) can be
+// computed against the same handler it's reported for, instead of against
+// the inflight count of every handler a Middleware measures.
+type inflightTracker struct {
+	mu    sync.Mutex
+	byHID map[string]*int64
+}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{byHID: map[string]*int64{}}
+}
+
+// add changes the inflight count of hid by delta and returns the new value.
+func (t *inflightTracker) add(hid string, delta int64) int64 {
+	t.mu.Lock()
+	counter, ok := t.byHID[hid]
+	if !ok {
+		counter = new(int64)
+		t.byHID[hid] = counter
 	}
+	t.mu.Unlock()
 
-	pathResult = strings.TrimRight(pathResult, "/")
-	return pathResult
+	return atomic.AddInt64(counter, delta)
+}
+
+// FixPath normalizes an URL path the way a NumericSegmentNormalizer does.
+//
+// Deprecated: use a PathNormalizer (e.g. NumericSegmentNormalizer) through
+// Config.PathNormalizer instead, this function is kept only for backwards
+// compatibility.
+func FixPath(urlPath string) string {
+	return NumericSegmentNormalizer{}.normalizePath(urlPath)
 }
 
 // Measure abstracts the HTTP handler implementation by only requesting a reporter, this
@@ -106,16 +164,28 @@ func FixPath(urlPath string) string {
 // it accepts a next function that will be called as the wrapped logic before and after
 // measurement actions.
 func (m Middleware) Measure(handlerID string, reporter Reporter, next func()) {
+	// Skip the measurement entirely (before touching inflight/duration/size/
+	// errors/saturation) if the request has been filtered out or didn't land
+	// in the sample.
+	if m.cfg.Filter != nil && !m.cfg.Filter(reporter) {
+		next()
+		return
+	}
+	if m.cfg.Sampler != nil && !sampled(m.cfg.Sampler(reporter)) {
+		next()
+		return
+	}
+
 	ctx := reporter.Context()
 
 	// If there isn't predefined handler ID we
 	// set that ID as the URL path.
 	hid := handlerID
 	if handlerID == "" {
-		hid = FixPath(reporter.URLPath())
+		hid = m.cfg.PathNormalizer.Normalize(reporter)
 	}
 
-	// Measure inflights if required.
+	// Measure inflights (and saturation, which depends on it) if required.
 	if !m.cfg.DisableMeasureInflight {
 		props := metrics.HTTPProperties{
 			Service: m.cfg.Service,
@@ -123,21 +193,29 @@ func (m Middleware) Measure(handlerID string, reporter Reporter, next func()) {
 		}
 		m.cfg.Recorder.AddInflightRequests(ctx, props, 1)
 		defer m.cfg.Recorder.AddInflightRequests(ctx, props, -1)
+
+		if m.cfg.SaturationFunc != nil {
+			inflightRequests := m.inflight.add(hid, 1)
+			defer m.inflight.add(hid, -1)
+
+			m.cfg.Recorder.ObserveSaturation(ctx, props, m.cfg.SaturationFunc(inflightRequests))
+		}
 	}
 
 	// Start the timer and when finishing measure the duration.
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
+		statusCode := reporter.StatusCode()
 
 		// If we need to group the status code, it uses the
 		// first number of the status code because is the least
 		// required identification way.
 		var code string
 		if m.cfg.GroupedStatus {
-			code = fmt.Sprintf("%dxx", reporter.StatusCode()/100)
+			code = fmt.Sprintf("%dxx", statusCode/100)
 		} else {
-			code = strconv.Itoa(reporter.StatusCode())
+			code = strconv.Itoa(statusCode)
 		}
 
 		props := metrics.HTTPReqProperties{
@@ -152,6 +230,15 @@ func (m Middleware) Measure(handlerID string, reporter Reporter, next func()) {
 		if !m.cfg.DisableMeasureSize {
 			m.cfg.Recorder.ObserveHTTPResponseSize(ctx, props, reporter.BytesWritten())
 		}
+
+		// Count the request as an error if the classifier says so.
+		var err error
+		if er, ok := reporter.(ErrReporter); ok {
+			err = er.Err()
+		}
+		if m.cfg.ErrorClassifier(statusCode, err) {
+			m.cfg.Recorder.AddHTTPRequestErrors(ctx, props, 1)
+		}
 	}()
 
 	// Call the wrapped logic.
@@ -167,3 +254,25 @@ type Reporter interface {
 	StatusCode() int
 	BytesWritten() int64
 }
+
+// RoutePatternReporter is an optional extension of Reporter that framework
+// adapters with a router (e.g. gin, echo, chi) can implement to expose the
+// route pattern that matched the request (e.g. `/users/{id}`) instead of the
+// raw URL path. TemplateNormalizer uses this to avoid the high cardinality
+// that comes from labeling metrics with the raw path.
+type RoutePatternReporter interface {
+	Reporter
+	// RoutePattern returns the router pattern that matched the request, or an
+	// empty string if the framework adapter doesn't expose one.
+	RoutePattern() string
+}
+
+// ErrReporter is an optional extension of Reporter that framework adapters
+// can implement to expose the error (if any) the handler produced, so
+// Config.ErrorClassifier can classify requests as an error even when they
+// don't surface as a 5xx status code.
+type ErrReporter interface {
+	Reporter
+	// Err returns the error the handler produced, or nil if there wasn't one.
+	Err() error
+}
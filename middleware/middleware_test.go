@@ -2,6 +2,7 @@ package middleware_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -134,6 +135,79 @@ func TestMiddlewareMeasure(t *testing.T) {
 				mrec.On("ObserveHTTPRequestDuration", mock.Anything, expRepProps, mock.Anything).Once()
 			},
 		},
+
+		"Having a 5xx response, it should count it as an error.": {
+			handlerID: "test01",
+			config: func() middleware.Config {
+				return middleware.Config{}
+			},
+			mock: func(mrec *mockmetrics.Recorder, mrep *mockmiddleware.Reporter) {
+				// Reporter mocks.
+				mrep.On("Context").Once().Return(context.TODO())
+				mrep.On("StatusCode").Once().Return(500)
+				mrep.On("Method").Once().Return("GET")
+				mrep.On("BytesWritten").Once().Return(int64(0))
+
+				// Recorder mocks.
+				expRepProps := metrics.HTTPReqProperties{ID: "test01", Method: "GET", Code: "500"}
+
+				mrec.On("AddInflightRequests", mock.Anything, mock.Anything, mock.Anything).Once()
+				mrec.On("AddInflightRequests", mock.Anything, mock.Anything, mock.Anything).Once()
+				mrec.On("ObserveHTTPRequestDuration", mock.Anything, expRepProps, mock.Anything).Once()
+				mrec.On("ObserveHTTPResponseSize", mock.Anything, expRepProps, int64(0)).Once()
+				mrec.On("AddHTTPRequestErrors", mock.Anything, expRepProps, 1).Once()
+			},
+		},
+
+		"Having a MaxInflight set, it should observe the saturation.": {
+			handlerID: "test01",
+			config: func() middleware.Config {
+				return middleware.Config{
+					MaxInflight: 2,
+				}
+			},
+			mock: func(mrec *mockmetrics.Recorder, mrep *mockmiddleware.Reporter) {
+				// Reporter mocks.
+				mrep.On("Context").Once().Return(context.TODO())
+				mrep.On("StatusCode").Once().Return(418)
+				mrep.On("Method").Once().Return("PATCH")
+				mrep.On("BytesWritten").Once().Return(int64(42))
+
+				// Recorder mocks.
+				expProps := metrics.HTTPProperties{ID: "test01"}
+				expRepProps := metrics.HTTPReqProperties{ID: "test01", Method: "PATCH", Code: "418"}
+
+				mrec.On("AddInflightRequests", mock.Anything, expProps, 1).Once()
+				mrec.On("AddInflightRequests", mock.Anything, expProps, -1).Once()
+				mrec.On("ObserveSaturation", mock.Anything, expProps, 0.5).Once()
+				mrec.On("ObserveHTTPRequestDuration", mock.Anything, expRepProps, mock.Anything).Once()
+				mrec.On("ObserveHTTPResponseSize", mock.Anything, expRepProps, int64(42)).Once()
+			},
+		},
+
+		"Having a filter that excludes the request, it shouldn't measure anything.": {
+			handlerID: "test01",
+			config: func() middleware.Config {
+				return middleware.Config{
+					Filter: func(r middleware.Reporter) bool { return false },
+				}
+			},
+			mock: func(mrec *mockmetrics.Recorder, mrep *mockmiddleware.Reporter) {
+				// No reporter nor recorder calls are expected.
+			},
+		},
+
+		"Having a sampler that never samples, it shouldn't measure anything.": {
+			handlerID: "test01",
+			config: func() middleware.Config {
+				return middleware.Config{
+					Sampler: func(r middleware.Reporter) float64 { return 0 },
+				}
+			},
+			mock: func(mrec *mockmetrics.Recorder, mrep *mockmiddleware.Reporter) {
+				// No reporter nor recorder calls are expected.
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -161,6 +235,46 @@ func TestMiddlewareMeasure(t *testing.T) {
 	}
 }
 
+// errReporter wraps a mockmiddleware.Reporter to also implement ErrReporter,
+// so the error classifier can be exercised with a non-nil error on a
+// non-5xx response.
+type errReporter struct {
+	*mockmiddleware.Reporter
+	err error
+}
+
+func (r errReporter) Err() error { return r.err }
+
+func TestMiddlewareMeasureErrReporter(t *testing.T) {
+	mrec := &mockmetrics.Recorder{}
+	mrep := &mockmiddleware.Reporter{}
+	reporter := errReporter{Reporter: mrep, err: errors.New("something failed")}
+
+	// Reporter mocks.
+	mrep.On("Context").Once().Return(context.TODO())
+	mrep.On("StatusCode").Once().Return(200)
+	mrep.On("Method").Once().Return("GET")
+	mrep.On("BytesWritten").Once().Return(int64(0))
+
+	// Recorder mocks.
+	expRepProps := metrics.HTTPReqProperties{ID: "test01", Method: "GET", Code: "200"}
+
+	mrec.On("AddInflightRequests", mock.Anything, mock.Anything, mock.Anything).Once()
+	mrec.On("AddInflightRequests", mock.Anything, mock.Anything, mock.Anything).Once()
+	mrec.On("ObserveHTTPRequestDuration", mock.Anything, expRepProps, mock.Anything).Once()
+	mrec.On("ObserveHTTPResponseSize", mock.Anything, expRepProps, int64(0)).Once()
+	mrec.On("AddHTTPRequestErrors", mock.Anything, expRepProps, 1).Once()
+
+	mdlw := middleware.New(middleware.Config{Recorder: mrec})
+
+	calledNext := false
+	mdlw.Measure("test01", reporter, func() { calledNext = true })
+
+	mrec.AssertExpectations(t)
+	mrep.AssertExpectations(t)
+	assert.True(t, calledNext)
+}
+
 func Test_fixPath(t *testing.T) {
 	res := middleware.FixPath("/api/v1/wallet/txs-history/0xe25fd558516cd5bf20b5d3dcb598dac566a61d1c")
 	assert.Equal(t, "/api/v1/wallet/txs-history/detail", res)
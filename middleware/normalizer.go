@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PathNormalizer knows how to turn the information exposed by a Reporter into
+// a label value with a bounded cardinality, it's used as the `ID` label when
+// the middleware hasn't been given an explicit handler ID.
+//
+// Implementations should avoid returning values derived directly from
+// unbounded input (e.g. raw URL paths with IDs in them), otherwise backends
+// like Prometheus will suffer from the high cardinality of the `ID` label.
+type PathNormalizer interface {
+	// Normalize returns the normalized value that will be used as the ID label.
+	Normalize(r Reporter) string
+}
+
+// RegexReplacement is a single pattern/replacement pair used by RegexNormalizer.
+type RegexReplacement struct {
+	// Pattern is the regex that will be matched against the URL path.
+	Pattern *regexp.Regexp
+	// Replace is the replacement applied on every match of Pattern, it follows
+	// the same semantics as regexp.Regexp.ReplaceAllString (it can reference
+	// capture groups with `$1`, `${name}`, ...).
+	Replace string
+}
+
+// RegexNormalizer normalizes the URL path by applying a user-supplied list of
+// regex replacements in order, e.g. replacing `/users/\d+` with `/users/{id}`.
+type RegexNormalizer struct {
+	Replacements []RegexReplacement
+}
+
+// Normalize implements PathNormalizer.
+func (n RegexNormalizer) Normalize(r Reporter) string {
+	path := r.URLPath()
+	for _, rp := range n.Replacements {
+		path = rp.Pattern.ReplaceAllString(path, rp.Replace)
+	}
+
+	return path
+}
+
+// TemplateNormalizer uses the router pattern matched by the framework (e.g.
+// `/users/{id}`) as the ID label instead of the raw URL path, this is the
+// standard fix for the high cardinality problem because the pattern is
+// already bounded by the number of routes registered in the router.
+//
+// If the Reporter doesn't implement RoutePatternReporter, or the route
+// pattern is empty (e.g. the request didn't match any route), it falls back
+// to the raw URL path.
+type TemplateNormalizer struct{}
+
+// Normalize implements PathNormalizer.
+func (TemplateNormalizer) Normalize(r Reporter) string {
+	if rpr, ok := r.(RoutePatternReporter); ok {
+		if pattern := rpr.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	return r.URLPath()
+}
+
+// NumericSegmentNormalizer generalizes numeric path segments (anything that
+// contains a digit and doesn't start with `v`, e.g. an API version like `v1`):
+// a numeric last segment is replaced with `detail` and numeric segments
+// elsewhere in the path are dropped, e.g. `/api/v1/users/123` becomes
+// `/api/v1/users/detail` and `/api/v1/orders/42/claim` becomes
+// `/api/v1/orders/claim`. Paths with three segments or fewer (e.g.
+// `/users/123`) are returned untouched.
+//
+// It doesn't strip static assets or any other path on its own anymore, use
+// Config.Filter (e.g. built with NewGlobPathFilter/NewRegexPathFilter) to
+// skip those requests entirely instead.
+type NumericSegmentNormalizer struct{}
+
+// Normalize implements PathNormalizer.
+func (n NumericSegmentNormalizer) Normalize(r Reporter) string {
+	return n.normalizePath(r.URLPath())
+}
+
+var digitRegexp = regexp.MustCompile(`\d`)
+
+func (NumericSegmentNormalizer) normalizePath(urlPath string) string {
+	tmpPaths := strings.Split(urlPath, "/")
+	n := len(tmpPaths)
+	if n <= 4 {
+		return urlPath
+	}
+
+	pathResult := ""
+	for i := 0; i < n; i++ {
+		if digitRegexp.MatchString(tmpPaths[i]) && !strings.HasPrefix(tmpPaths[i], "v") {
+			if i == n-1 {
+				pathResult += "detail"
+			}
+		} else {
+			pathResult += tmpPaths[i] + "/"
+		}
+	}
+
+	return strings.TrimRight(pathResult, "/")
+}
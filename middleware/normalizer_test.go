@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/go-http-metrics/middleware"
+)
+
+// fakeReporter is a minimal middleware.Reporter used to test PathNormalizer
+// implementations without pulling in the full mock.
+type fakeReporter struct {
+	urlPath      string
+	routePattern string
+}
+
+func (f fakeReporter) Method() string           { return "GET" }
+func (f fakeReporter) Context() context.Context { return context.TODO() }
+func (f fakeReporter) URLPath() string          { return f.urlPath }
+func (f fakeReporter) StatusCode() int          { return 200 }
+func (f fakeReporter) BytesWritten() int64      { return 0 }
+func (f fakeReporter) RoutePattern() string     { return f.routePattern }
+
+func TestRegexNormalizer(t *testing.T) {
+	n := middleware.RegexNormalizer{
+		Replacements: []middleware.RegexReplacement{
+			{Pattern: regexp.MustCompile(`/\d+`), Replace: "/{id}"},
+		},
+	}
+
+	res := n.Normalize(fakeReporter{urlPath: "/api/v1/users/123"})
+	assert.Equal(t, "/api/v1/users/{id}", res)
+}
+
+func TestTemplateNormalizer(t *testing.T) {
+	tests := map[string]struct {
+		reporter fakeReporter
+		exp      string
+	}{
+		"With a route pattern, it should use it.": {
+			reporter: fakeReporter{urlPath: "/api/v1/users/123", routePattern: "/api/v1/users/{id}"},
+			exp:      "/api/v1/users/{id}",
+		},
+		"Without a route pattern, it should fall back to the URL path.": {
+			reporter: fakeReporter{urlPath: "/api/v1/users/123"},
+			exp:      "/api/v1/users/123",
+		},
+	}
+
+	n := middleware.TemplateNormalizer{}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := n.Normalize(test.reporter)
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
+
+func TestNumericSegmentNormalizer(t *testing.T) {
+	n := middleware.NumericSegmentNormalizer{}
+
+	tests := map[string]struct {
+		urlPath string
+		exp     string
+	}{
+		"A short path should be left untouched.": {urlPath: "/api/v1/brands", exp: "/api/v1/brands"},
+		"A numeric last segment becomes detail.": {urlPath: "/api/v1/brands/123", exp: "/api/v1/brands/detail"},
+		"A non-numeric last segment is kept.":    {urlPath: "/api/v1/brands/cashback", exp: "/api/v1/brands/cashback"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := n.Normalize(fakeReporter{urlPath: test.urlPath})
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
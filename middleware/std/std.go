@@ -0,0 +1,66 @@
+// Package std is a helper package to create measuring middlewares for
+// net/http compatible handlers.
+package std
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/slok/go-http-metrics/middleware"
+)
+
+// Handler returns a measuring http.Handler that wraps h. If handlerID is
+// empty, Config.PathNormalizer is used to derive the ID label from the
+// request.
+func Handler(handlerID string, m middleware.Middleware, h http.Handler) http.Handler {
+	return HandlerWithPattern(handlerID, "", m, h)
+}
+
+// HandlerWithPattern is like Handler but also receives the router pattern
+// that matched the request (e.g. `/users/{id}` from a ServeMux, gorilla/mux
+// or chi route). The pattern is exposed through middleware.RoutePatternReporter
+// so middleware.TemplateNormalizer can use it instead of the raw URL path.
+func HandlerWithPattern(handlerID, pattern string, m middleware.Middleware, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriterInterceptor{ResponseWriter: w, statusCode: http.StatusOK}
+
+		m.Measure(handlerID, stdReporter{w: rw, r: r, pattern: pattern}, func() {
+			h.ServeHTTP(rw, r)
+		})
+	})
+}
+
+type stdReporter struct {
+	w       *responseWriterInterceptor
+	r       *http.Request
+	pattern string
+}
+
+func (s stdReporter) Method() string           { return s.r.Method }
+func (s stdReporter) Context() context.Context { return s.r.Context() }
+func (s stdReporter) URLPath() string          { return s.r.URL.Path }
+func (s stdReporter) StatusCode() int          { return s.w.statusCode }
+func (s stdReporter) BytesWritten() int64      { return s.w.bytesWritten }
+
+// RoutePattern satisfies middleware.RoutePatternReporter.
+func (s stdReporter) RoutePattern() string { return s.pattern }
+
+// responseWriterInterceptor wraps an http.ResponseWriter to capture the
+// status code and the number of bytes written, the two things net/http
+// doesn't expose after the handler has run.
+type responseWriterInterceptor struct {
+	http.ResponseWriter
+	statusCode   int
+	bytesWritten int64
+}
+
+func (w *responseWriterInterceptor) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseWriterInterceptor) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytesWritten += int64(n)
+	return n, err
+}
@@ -0,0 +1,61 @@
+package std_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/slok/go-http-metrics/middleware"
+	"github.com/slok/go-http-metrics/middleware/std"
+)
+
+// recordingNormalizer wraps a PathNormalizer to capture the ID it produced,
+// so the test can assert on it without needing a mock package.
+type recordingNormalizer struct {
+	inner middleware.PathNormalizer
+	got   *string
+}
+
+func (n recordingNormalizer) Normalize(r middleware.Reporter) string {
+	id := n.inner.Normalize(r)
+	*n.got = id
+	return id
+}
+
+func TestHandlerWithPattern(t *testing.T) {
+	var gotID string
+	normalizer := recordingNormalizer{inner: middleware.TemplateNormalizer{}, got: &gotID}
+	mdlw := middleware.New(middleware.Config{PathNormalizer: normalizer})
+
+	h := std.HandlerWithPattern("", "/users/{id}", mdlw, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/123", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "hello", rec.Body.String())
+	assert.Equal(t, "/users/{id}", gotID, "TemplateNormalizer should have used the route pattern exposed by stdReporter")
+}
+
+func TestHandler(t *testing.T) {
+	var gotID string
+	normalizer := recordingNormalizer{inner: middleware.TemplateNormalizer{}, got: &gotID}
+	mdlw := middleware.New(middleware.Config{PathNormalizer: normalizer})
+
+	h := std.Handler("", mdlw, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/123", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/users/123", gotID, "without a pattern, TemplateNormalizer should fall back to the raw URL path")
+}